@@ -0,0 +1,131 @@
+package db
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randStr(length int) string {
+	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(b)
+}
+
+func cleanupDBDir(dir, name string) {
+	_ = os.RemoveAll(filepath.Join(dir, name) + ".db")
+}
+
+// Run exercises the common DB contract (Get/Set/Delete/Iterator/Batch/...)
+// against db, regardless of backend.
+func Run(t *testing.T, db DB) {
+	t.Helper()
+
+	t.Run("GetSetDelete", func(t *testing.T) {
+		val, err := db.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Nil(t, val)
+
+		require.NoError(t, db.Set([]byte("a"), []byte("1")))
+		val, err = db.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), val)
+
+		require.NoError(t, db.Delete([]byte("a")))
+		val, err = db.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Nil(t, val)
+	})
+
+	t.Run("DeleteRange", func(t *testing.T) {
+		for _, k := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, db.Set([]byte(k), []byte(k)))
+		}
+
+		require.NoError(t, db.DeleteRange([]byte("b"), []byte("d")))
+
+		for _, tc := range []struct {
+			key     string
+			deleted bool
+		}{
+			{"a", false},
+			{"b", true},
+			{"c", true},
+			{"d", false},
+		} {
+			val, err := db.Get([]byte(tc.key))
+			require.NoError(t, err)
+			if tc.deleted {
+				assert.Nil(t, val)
+			} else {
+				assert.Equal(t, []byte(tc.key), val)
+			}
+			require.NoError(t, db.Delete([]byte(tc.key)))
+		}
+	})
+
+	t.Run("Iterator", func(t *testing.T) {
+		require.NoError(t, db.Set([]byte("a"), []byte("1")))
+		require.NoError(t, db.Set([]byte("b"), []byte("2")))
+		defer func() {
+			require.NoError(t, db.Delete([]byte("a")))
+			require.NoError(t, db.Delete([]byte("b")))
+		}()
+
+		itr, err := db.Iterator(nil, nil)
+		require.NoError(t, err)
+		defer itr.Close()
+
+		var keys []string
+		for ; itr.Valid(); itr.Next() {
+			keys = append(keys, string(itr.Key()))
+		}
+		assert.Equal(t, []string{"a", "b"}, keys)
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		batch := db.NewBatch()
+		defer batch.Close()
+
+		require.NoError(t, batch.Set([]byte("x"), []byte("1")))
+		require.NoError(t, batch.Write())
+
+		val, err := db.Get([]byte("x"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), val)
+
+		require.NoError(t, db.Delete([]byte("x")))
+	})
+}
+
+// RunBatchDeleteRangeSemantics pins that a batch applies its ops in staging
+// order on every backend: a Set to a key in a range already staged for
+// deletion still wins at Write, whether DeleteRange is backed by a native
+// range tombstone (PebbleDB) or by point deletes staged at call time
+// (GoLevelDB, MemDB).
+func RunBatchDeleteRangeSemantics(t *testing.T, db DB) {
+	t.Helper()
+
+	require.NoError(t, db.Set([]byte("b"), []byte("b")))
+	defer func() {
+		require.NoError(t, db.Delete([]byte("b")))
+	}()
+
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	require.NoError(t, batch.DeleteRange([]byte("b"), []byte("c")))
+	require.NoError(t, batch.Set([]byte("b"), []byte("late")))
+	require.NoError(t, batch.Write())
+
+	val, err := db.Get([]byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("late"), val)
+}
@@ -0,0 +1,209 @@
+package db
+
+import "fmt"
+
+// DB is the interface every backend (GoLevelDB, MemDB, PebbleDB, ...)
+// implements.
+//
+// For all methods, keys and values should be non-nil. Setting a value to
+// nil is equivalent to deleting the corresponding key. Keys and values
+// should be considered read-only, both when returned and when given as
+// arguments.
+type DB interface {
+	// Get fetches the value of the given key, or nil if it does not exist.
+	// errKeyEmpty is returned if key is empty.
+	Get([]byte) ([]byte, error)
+
+	// Has checks if a key exists.
+	// errKeyEmpty is returned if key is empty.
+	Has(key []byte) (bool, error)
+
+	// Set sets the value for the given key, replacing it if it already
+	// exists.
+	// errKeyEmpty is returned if key is empty, errValueNil if value is nil.
+	Set([]byte, []byte) error
+
+	// SetSync is the same as Set, but flushed to disk before returning.
+	SetSync([]byte, []byte) error
+
+	// Delete deletes the key, or does nothing if the key does not exist.
+	// errKeyEmpty is returned if key is empty.
+	Delete([]byte) error
+
+	// DeleteSync is the same as Delete, but flushed to disk before
+	// returning.
+	DeleteSync([]byte) error
+
+	// DeleteRange deletes every key in [start, end), matching Iterator's
+	// half-open domain: start is inclusive, end is exclusive. Backends
+	// without a native range-delete primitive implement this via
+	// DeleteRangeFallback, iterating and deleting under a single batch.
+	// errKeyEmpty is returned if start or end is empty.
+	DeleteRange(start, end []byte) error
+
+	// Iterator returns an iterator over a domain of keys, in ascending
+	// order. The caller must call Close when done. End is exclusive, and
+	// start must be less than end. A nil start iterates from the first
+	// key, and a nil end iterates to the last.
+	// errKeyEmpty is returned if start or end is empty (but not nil).
+	Iterator(start, end []byte) (Iterator, error)
+
+	// ReverseIterator returns an iterator over a domain of keys, in
+	// descending order. Same domain semantics as Iterator.
+	ReverseIterator(start, end []byte) (Iterator, error)
+
+	// Close closes the database connection.
+	Close() error
+
+	// NewBatch creates a batch for atomic updates.
+	NewBatch() Batch
+
+	// Print is used for debugging.
+	Print() error
+
+	// Stats returns a map of property values for the backend's internal
+	// state.
+	Stats() map[string]string
+}
+
+// Setter is implemented by anything that supports Set, which is shared by
+// DB and Batch.
+type Setter interface {
+	Set(key, value []byte) error
+}
+
+// Batch represents a group of writes that may be committed together.
+// Batches are not goroutine-safe, and should only be used once before
+// discarding (via Write or Close).
+type Batch interface {
+	Setter
+
+	// Delete adds a delete operation to the batch.
+	Delete(key []byte) error
+
+	// DeleteRange adds a range-delete operation to the batch, matching
+	// DB.DeleteRange's half-open [start, end) domain.
+	//
+	// Ops within a batch are applied in staging order on every backend, so
+	// a Set to a key in [start, end) staged after this call still wins,
+	// whether DeleteRange is backed by a native range tombstone (PebbleDB)
+	// or by point deletes for the keys found in [start, end) at call time
+	// (GoLevelDB, MemDB).
+	DeleteRange(start, end []byte) error
+
+	// Write commits the batch, possibly without flushing to disk. Only
+	// Close can be called afterwards.
+	Write() error
+
+	// WriteSync commits the batch and flushes it to disk. Only Close can
+	// be called afterwards.
+	WriteSync() error
+
+	// Close discards the uncommitted batch. Implementations must be safe
+	// to call multiple times, and after Write/WriteSync.
+	Close() error
+}
+
+// Iterator represents an iterator over a domain of keys. Callers must call
+// Close when done. No writes to the underlying DB may happen while an
+// iterator is still active.
+type Iterator interface {
+	// Domain returns the start (inclusive) and end (exclusive) limits the
+	// iterator was created with.
+	Domain() (start []byte, end []byte)
+
+	// Valid returns whether the iterator is positioned at a valid entry.
+	Valid() bool
+
+	// Next moves the iterator to the next entry.
+	Next()
+
+	// Key returns the key at the current position. Panics if not Valid.
+	Key() (key []byte)
+
+	// Value returns the value at the current position. Panics if not
+	// Valid.
+	Value() (value []byte)
+
+	// Error returns the last error encountered by the iterator, if any.
+	Error() error
+
+	// Close closes the iterator, releasing any allocated resources.
+	Close() error
+}
+
+// Snapshotter is implemented by DB backends that can take a point-in-time
+// read-only Snapshot.
+type Snapshotter interface {
+	// Snapshot returns a new Snapshot. The caller must call Release when done.
+	Snapshot() (Snapshot, error)
+}
+
+// Snapshot is an immutable, point-in-time read-only view of a DB.
+type Snapshot interface {
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+	Iterator(start, end []byte) (Iterator, error)
+	ReverseIterator(start, end []byte) (Iterator, error)
+
+	// Release releases the snapshot. It must not be used afterwards.
+	Release()
+}
+
+// Checkpointer is implemented by DB backends that can create a consistent,
+// on-disk copy of their current state at destDir.
+type Checkpointer interface {
+	// Checkpoint creates a copy of the DB's current state at destDir.
+	// destDir must not already exist.
+	Checkpoint(destDir string, opts ...CheckpointOption) error
+}
+
+// checkpointConfig holds the options accumulated from a Checkpoint call's
+// CheckpointOption arguments.
+type checkpointConfig struct {
+	flushWAL bool
+}
+
+// CheckpointOption configures a Checkpoint call.
+type CheckpointOption func(*checkpointConfig)
+
+// WithFlushedWAL flushes the WAL before creating the checkpoint.
+func WithFlushedWAL() CheckpointOption {
+	return func(c *checkpointConfig) {
+		c.flushWAL = true
+	}
+}
+
+// BackendType names a DB backend known to NewDB.
+type BackendType string
+
+const (
+	GoLevelDBBackend BackendType = "goleveldb"
+	MemDBBackend     BackendType = "memdb"
+	PebbleDBBackend  BackendType = "pebbledb"
+)
+
+type dbCreator func(name string, dir string) (DB, error)
+
+var backends = map[BackendType]dbCreator{}
+
+// registerDBCreator registers a function that opens a DB of the given
+// backend type. If force is false and a creator is already registered for
+// backend, it is left untouched.
+func registerDBCreator(backend BackendType, creator dbCreator, force bool) {
+	_, ok := backends[backend]
+	if !force && ok {
+		return
+	}
+	backends[backend] = creator
+}
+
+// NewDB opens a DB of the given backend type, in the given dir, named
+// name.
+func NewDB(name string, backend BackendType, dir string) (DB, error) {
+	dbCreator, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown db backend %q", backend)
+	}
+	return dbCreator(name, dir)
+}
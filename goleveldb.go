@@ -0,0 +1,374 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func init() {
+	dbCreator := func(name string, dir string) (DB, error) {
+		return NewGoLevelDB(name, dir)
+	}
+	registerDBCreator(GoLevelDBBackend, dbCreator, false)
+}
+
+// GoLevelDB is a DB backend wrapping syndtr/goleveldb.
+type GoLevelDB struct {
+	db *leveldb.DB
+}
+
+var _ DB = (*GoLevelDB)(nil)
+
+func NewGoLevelDB(name string, dir string) (*GoLevelDB, error) {
+	return NewGoLevelDBWithOpts(name, dir, nil)
+}
+
+func NewGoLevelDBWithOpts(name string, dir string, o *opt.Options) (*GoLevelDB, error) {
+	dbPath := filepath.Join(dir, name+".db")
+
+	db, err := leveldb.OpenFile(dbPath, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoLevelDB{db: db}, nil
+}
+
+// Get implements DB.
+func (db *GoLevelDB) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errKeyEmpty
+	}
+
+	res, err := db.db.Get(key, nil)
+	if err != nil {
+		if err == errors.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Has implements DB.
+func (db *GoLevelDB) Has(key []byte) (bool, error) {
+	if len(key) == 0 {
+		return false, errKeyEmpty
+	}
+
+	return db.db.Has(key, nil)
+}
+
+// Set implements DB.
+func (db *GoLevelDB) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+
+	return db.db.Put(key, value, nil)
+}
+
+// SetSync implements DB.
+func (db *GoLevelDB) SetSync(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+
+	return db.db.Put(key, value, &opt.WriteOptions{Sync: true})
+}
+
+// Delete implements DB.
+func (db *GoLevelDB) Delete(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+
+	return db.db.Delete(key, nil)
+}
+
+// DeleteSync implements DB.
+func (db *GoLevelDB) DeleteSync(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+
+	return db.db.Delete(key, &opt.WriteOptions{Sync: true})
+}
+
+// DeleteRange implements DB. goleveldb has no native range-delete
+// primitive, so this falls back to iterating [start, end) and deleting
+// every key under a single batch.
+func (db *GoLevelDB) DeleteRange(start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+
+	return DeleteRangeFallback(db, start, end)
+}
+
+// Close implements DB.
+func (db *GoLevelDB) Close() error {
+	return db.db.Close()
+}
+
+// Print implements DB.
+func (db *GoLevelDB) Print() error {
+	itr, err := db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", itr.Key(), itr.Value())
+	}
+
+	return nil
+}
+
+// Stats implements DB.
+func (db *GoLevelDB) Stats() map[string]string {
+	keys := []string{
+		"leveldb.num-files-at-level{n}",
+		"leveldb.stats",
+		"leveldb.sstables",
+		"leveldb.blockpool",
+		"leveldb.cachedblock",
+		"leveldb.openedtables",
+		"leveldb.alivesnaps",
+		"leveldb.aliveiters",
+	}
+
+	stats := make(map[string]string)
+	for _, key := range keys {
+		str, err := db.db.GetProperty(key)
+		if err == nil {
+			stats[key] = str
+		}
+	}
+
+	return stats
+}
+
+// NewBatch implements DB.
+func (db *GoLevelDB) NewBatch() Batch {
+	return newGoLevelDBBatch(db)
+}
+
+func (db *GoLevelDB) newIterator(start, end []byte, isReverse bool) (Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, errKeyEmpty
+	}
+
+	var slice *util.Range
+	if start != nil || end != nil {
+		slice = &util.Range{Start: start, Limit: end}
+	}
+
+	itr := db.db.NewIterator(slice, nil)
+
+	return newGoLevelDBIterator(itr, start, end, isReverse), nil
+}
+
+// Iterator implements DB.
+func (db *GoLevelDB) Iterator(start, end []byte) (Iterator, error) {
+	return db.newIterator(start, end, false)
+}
+
+// ReverseIterator implements DB.
+func (db *GoLevelDB) ReverseIterator(start, end []byte) (Iterator, error) {
+	return db.newIterator(start, end, true)
+}
+
+type goLevelDBBatch struct {
+	db    *GoLevelDB
+	batch *leveldb.Batch
+}
+
+var _ Batch = (*goLevelDBBatch)(nil)
+
+func newGoLevelDBBatch(db *GoLevelDB) *goLevelDBBatch {
+	return &goLevelDBBatch{db: db, batch: new(leveldb.Batch)}
+}
+
+// Set implements Batch.
+func (b *goLevelDBBatch) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	b.batch.Put(key, value)
+	return nil
+}
+
+// Delete implements Batch.
+func (b *goLevelDBBatch) Delete(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	b.batch.Delete(key)
+	return nil
+}
+
+// DeleteRange implements Batch via the same iterate-and-delete
+// fallback used by DB.DeleteRange, staging the deletes into this batch
+// instead of committing them immediately.
+func (b *goLevelDBBatch) DeleteRange(start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	itr, err := b.db.Iterator(start, end)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		b.batch.Delete(itr.Key())
+	}
+
+	return itr.Error()
+}
+
+// Write implements Batch.
+func (b *goLevelDBBatch) Write() error {
+	return b.write(false)
+}
+
+// WriteSync implements Batch.
+func (b *goLevelDBBatch) WriteSync() error {
+	return b.write(true)
+}
+
+func (b *goLevelDBBatch) write(sync bool) error {
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	err := b.db.db.Write(b.batch, &opt.WriteOptions{Sync: sync})
+	if err != nil {
+		return err
+	}
+
+	return b.Close()
+}
+
+// Close implements Batch.
+func (b *goLevelDBBatch) Close() error {
+	if b.batch != nil {
+		b.batch.Reset()
+		b.batch = nil
+	}
+	return nil
+}
+
+type goLevelDBIterator struct {
+	source     iterator.Iterator
+	start, end []byte
+	isReverse  bool
+	isInvalid  bool
+}
+
+var _ Iterator = (*goLevelDBIterator)(nil)
+
+func newGoLevelDBIterator(source iterator.Iterator, start, end []byte, isReverse bool) *goLevelDBIterator {
+	if isReverse {
+		source.Last()
+	} else {
+		source.First()
+	}
+
+	return &goLevelDBIterator{
+		source:    source,
+		start:     start,
+		end:       end,
+		isReverse: isReverse,
+		isInvalid: false,
+	}
+}
+
+// Domain implements Iterator.
+func (itr *goLevelDBIterator) Domain() ([]byte, []byte) {
+	return itr.start, itr.end
+}
+
+// Valid implements Iterator.
+func (itr *goLevelDBIterator) Valid() bool {
+	if itr.isInvalid {
+		return false
+	}
+
+	if !itr.source.Valid() {
+		itr.isInvalid = true
+		return false
+	}
+
+	return true
+}
+
+// Next implements Iterator.
+func (itr *goLevelDBIterator) Next() {
+	itr.assertIsValid()
+
+	if itr.isReverse {
+		itr.source.Prev()
+	} else {
+		itr.source.Next()
+	}
+}
+
+// Key implements Iterator.
+func (itr *goLevelDBIterator) Key() []byte {
+	itr.assertIsValid()
+	return cp(itr.source.Key())
+}
+
+// Value implements Iterator.
+func (itr *goLevelDBIterator) Value() []byte {
+	itr.assertIsValid()
+	return cp(itr.source.Value())
+}
+
+// Error implements Iterator.
+func (itr *goLevelDBIterator) Error() error {
+	return itr.source.Error()
+}
+
+// Close implements Iterator.
+func (itr *goLevelDBIterator) Close() error {
+	itr.source.Release()
+	return nil
+}
+
+func (itr *goLevelDBIterator) assertIsValid() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+}
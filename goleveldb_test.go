@@ -0,0 +1,33 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoLevelDBBackend(t *testing.T) {
+	name := fmt.Sprintf("test_%x", randStr(12))
+	dir := os.TempDir()
+	db, err := NewDB(name, GoLevelDBBackend, dir)
+	require.NoError(t, err)
+	defer cleanupDBDir(dir, name)
+
+	_, ok := db.(*GoLevelDB)
+	assert.True(t, ok)
+}
+
+func TestWithGoLevelDB(t *testing.T) {
+	name := fmt.Sprintf("test_%x", randStr(12))
+	db, err := NewGoLevelDB(name, os.TempDir())
+	require.NoError(t, err)
+	defer cleanupDBDir(os.TempDir(), name)
+
+	t.Run("GoLevelDB", func(t *testing.T) { Run(t, db) })
+	t.Run("BatchDeleteRangeSemantics", func(t *testing.T) {
+		RunBatchDeleteRangeSemantics(t, db)
+	})
+}
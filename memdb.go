@@ -0,0 +1,367 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/google/btree"
+)
+
+const memDBBTreeDegree = 32
+
+func init() {
+	dbCreator := func(name string, dir string) (DB, error) {
+		return NewMemDB(), nil
+	}
+	registerDBCreator(MemDBBackend, dbCreator, false)
+}
+
+// MemDB is an in-memory DB backend, backed by a btree for ordered
+// iteration. It is safe for concurrent use.
+type MemDB struct {
+	mtx  sync.RWMutex
+	tree *btree.BTree
+}
+
+var _ DB = (*MemDB)(nil)
+
+// NewMemDB creates a new, empty in-memory DB.
+func NewMemDB() *MemDB {
+	return &MemDB{tree: btree.New(memDBBTreeDegree)}
+}
+
+type memDBItem struct {
+	key, value []byte
+}
+
+func (i *memDBItem) Less(other btree.Item) bool {
+	return bytes.Compare(i.key, other.(*memDBItem).key) == -1
+}
+
+func newMemDBKey(key []byte) *memDBItem {
+	return &memDBItem{key: key}
+}
+
+// Get implements DB.
+func (db *MemDB) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errKeyEmpty
+	}
+
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	i := db.tree.Get(newMemDBKey(key))
+	if i == nil {
+		return nil, nil
+	}
+
+	return cp(i.(*memDBItem).value), nil
+}
+
+// Has implements DB.
+func (db *MemDB) Has(key []byte) (bool, error) {
+	if len(key) == 0 {
+		return false, errKeyEmpty
+	}
+
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	return db.tree.Has(newMemDBKey(key)), nil
+}
+
+// Set implements DB.
+func (db *MemDB) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	db.set(key, value)
+	return nil
+}
+
+func (db *MemDB) set(key, value []byte) {
+	db.tree.ReplaceOrInsert(&memDBItem{key: cp(key), value: cp(value)})
+}
+
+// SetSync implements DB.
+func (db *MemDB) SetSync(key, value []byte) error {
+	return db.Set(key, value)
+}
+
+// Delete implements DB.
+func (db *MemDB) Delete(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	db.tree.Delete(newMemDBKey(key))
+	return nil
+}
+
+// DeleteSync implements DB.
+func (db *MemDB) DeleteSync(key []byte) error {
+	return db.Delete(key)
+}
+
+// DeleteRange implements DB. MemDB has no range-delete primitive either,
+// so this falls back to iterating [start, end) and deleting every key
+// under a single batch, same as GoLevelDB.
+func (db *MemDB) DeleteRange(start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+
+	return DeleteRangeFallback(db, start, end)
+}
+
+// Close implements DB.
+func (db *MemDB) Close() error {
+	return nil
+}
+
+// Print implements DB.
+func (db *MemDB) Print() error {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	db.tree.Ascend(func(i btree.Item) bool {
+		item := i.(*memDBItem)
+		fmt.Printf("[%X]:\t[%X]\n", item.key, item.value)
+		return true
+	})
+
+	return nil
+}
+
+// Stats implements DB.
+func (db *MemDB) Stats() map[string]string {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	return map[string]string{
+		"database.type": "memDB",
+		"database.size": fmt.Sprintf("%d", db.tree.Len()),
+	}
+}
+
+// NewBatch implements DB.
+func (db *MemDB) NewBatch() Batch {
+	return newMemDBBatch(db)
+}
+
+// getRange returns a copy of every key/value pair in [start, end), in
+// ascending or descending order.
+func (db *MemDB) getRange(start, end []byte, reverse bool) [][2][]byte {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	var items [][2][]byte
+	visit := func(i btree.Item) bool {
+		item := i.(*memDBItem)
+		items = append(items, [2][]byte{cp(item.key), cp(item.value)})
+		return true
+	}
+
+	// Always walk in ascending order over [start, end), then reverse the
+	// result if needed: btree's descend-range variants are inclusive at
+	// the wrong end for our half-open domain, so it's simpler to get this
+	// right once and flip the slice afterwards.
+	switch {
+	case start == nil && end == nil:
+		db.tree.Ascend(visit)
+	case start == nil:
+		db.tree.AscendLessThan(newMemDBKey(end), visit)
+	case end == nil:
+		db.tree.AscendGreaterOrEqual(newMemDBKey(start), visit)
+	default:
+		db.tree.AscendRange(newMemDBKey(start), newMemDBKey(end), visit)
+	}
+
+	if reverse {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	return items
+}
+
+// Iterator implements DB.
+func (db *MemDB) Iterator(start, end []byte) (Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, errKeyEmpty
+	}
+
+	return newMemDBIterator(db.getRange(start, end, false), start, end), nil
+}
+
+// ReverseIterator implements DB.
+func (db *MemDB) ReverseIterator(start, end []byte) (Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, errKeyEmpty
+	}
+
+	return newMemDBIterator(db.getRange(start, end, true), start, end), nil
+}
+
+type memDBBatchOp struct {
+	delete bool
+	key    []byte
+	value  []byte
+}
+
+type memDBBatch struct {
+	db  *MemDB
+	ops []memDBBatchOp
+}
+
+var _ Batch = (*memDBBatch)(nil)
+
+func newMemDBBatch(db *MemDB) *memDBBatch {
+	return &memDBBatch{db: db}
+}
+
+// Set implements Batch.
+func (b *memDBBatch) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+	if b.db == nil {
+		return errBatchClosed
+	}
+
+	b.ops = append(b.ops, memDBBatchOp{key: key, value: value})
+	return nil
+}
+
+// Delete implements Batch.
+func (b *memDBBatch) Delete(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if b.db == nil {
+		return errBatchClosed
+	}
+
+	b.ops = append(b.ops, memDBBatchOp{delete: true, key: key})
+	return nil
+}
+
+// DeleteRange implements Batch, staging a delete op for every key
+// currently in [start, end) into this batch.
+func (b *memDBBatch) DeleteRange(start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+
+	for _, kv := range b.db.getRange(start, end, false) {
+		b.ops = append(b.ops, memDBBatchOp{delete: true, key: kv[0]})
+	}
+
+	return nil
+}
+
+// Write implements Batch.
+func (b *memDBBatch) Write() error {
+	if b.db == nil {
+		return errBatchClosed
+	}
+
+	b.db.mtx.Lock()
+	defer b.db.mtx.Unlock()
+
+	for _, op := range b.ops {
+		if op.delete {
+			b.db.tree.Delete(newMemDBKey(op.key))
+		} else {
+			b.db.set(op.key, op.value)
+		}
+	}
+
+	return b.Close()
+}
+
+// WriteSync implements Batch.
+func (b *memDBBatch) WriteSync() error {
+	return b.Write()
+}
+
+// Close implements Batch.
+func (b *memDBBatch) Close() error {
+	b.ops = nil
+	b.db = nil
+	return nil
+}
+
+type memDBIterator struct {
+	items []([2][]byte)
+	start []byte
+	end   []byte
+	pos   int
+}
+
+var _ Iterator = (*memDBIterator)(nil)
+
+func newMemDBIterator(items [][2][]byte, start, end []byte) *memDBIterator {
+	return &memDBIterator{items: items, start: start, end: end}
+}
+
+// Domain implements Iterator.
+func (itr *memDBIterator) Domain() ([]byte, []byte) {
+	return itr.start, itr.end
+}
+
+// Valid implements Iterator.
+func (itr *memDBIterator) Valid() bool {
+	return itr.pos >= 0 && itr.pos < len(itr.items)
+}
+
+// Next implements Iterator.
+func (itr *memDBIterator) Next() {
+	itr.assertIsValid()
+	itr.pos++
+}
+
+// Key implements Iterator.
+func (itr *memDBIterator) Key() []byte {
+	itr.assertIsValid()
+	return itr.items[itr.pos][0]
+}
+
+// Value implements Iterator.
+func (itr *memDBIterator) Value() []byte {
+	itr.assertIsValid()
+	return itr.items[itr.pos][1]
+}
+
+// Error implements Iterator.
+func (itr *memDBIterator) Error() error {
+	return nil
+}
+
+// Close implements Iterator.
+func (itr *memDBIterator) Close() error {
+	itr.items = nil
+	return nil
+}
+
+func (itr *memDBIterator) assertIsValid() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+}
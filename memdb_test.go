@@ -0,0 +1,25 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemDBBackend(t *testing.T) {
+	db, err := NewDB("test", MemDBBackend, "")
+	require.NoError(t, err)
+
+	_, ok := db.(*MemDB)
+	assert.True(t, ok)
+}
+
+func TestWithMemDB(t *testing.T) {
+	db := NewMemDB()
+
+	t.Run("MemDB", func(t *testing.T) { Run(t, db) })
+	t.Run("BatchDeleteRangeSemantics", func(t *testing.T) {
+		RunBatchDeleteRangeSemantics(t, db)
+	})
+}
@@ -6,50 +6,35 @@ package db
 import (
 	"fmt"
 	"path/filepath"
+	"sync/atomic"
 
 	"github.com/cockroachdb/pebble"
 )
 
-// PebbleDbForceSync is a flag to force using Sync for some PebbleDB functions.
-// It should be set at compile time to have effect.
-//
-// How to use it:
-//
-//   go mod edit -replace=github.com/tendermint/tm-db=github.com/ChorusOne/tm-db@$v0.1.5-pebbledb
-//   go mod tidy
-//
-//   # For regular use
-//   make BUILD_TAGS=pebbledb LDFLAGS="-w -s -X github.com/cosmos/cosmos-sdk/types.DBBackend=pebbledb" install
-//
-//   # In case db is not flushed to disk
-//   make BUILD_TAGS=pebbledb LDFLAGS="-w -s -X github.com/cosmos/cosmos-sdk/types.DBBackend=pebbledb -X github.com/tendermint/tm-db.PebbleDbForceSync=1" install
-//
-//   <appd> start --db_backend=pebbledb
-//
-// When db cannot be flushed to disk caused by SDK panic,
-// PebbleDbForceSync should be used with the same version (the version
-// before the upgrade) to properly flush data to disk.
-var PebbleDbForceSync = "0"
-var isPebbleDbForceSync = false
+// NOTE: PebbleDbForceSync, the compile-time -X ldflag that forced every
+// Set/Delete on every PebbleDB to use Sync, has been removed (see
+// cometbft-db#125 for the rationale: it was an all-or-nothing global that
+// could only be toggled at build time, and silently hurt throughput for
+// callers already using SetSync/DeleteSync deliberately). Callers relying
+// on it to recover from an unclean shutdown should instead call
+// SetDefaultSyncMode(true) on the affected PebbleDB, or set
+// PebbleOptions.DefaultSync before opening it.
 
 func init() {
 	dbCreator := func(name string, dir string) (DB, error) {
-		return NewPebbleDB(name, dir)
+		cfg := pebbleOptionsFromEnv(currentPebbleOptions())
+		return NewPebbleDBWithConfig(name, dir, cfg)
 	}
 
 	registerDBCreator(PebbleDBBackend, dbCreator, false)
-
-	if PebbleDbForceSync == "1" {
-		isPebbleDbForceSync = true
-	} else if PebbleDbForceSync == "0" {
-		isPebbleDbForceSync = false
-	} else {
-		panic("PebbleDbForceSync should be 0 or 1, but provided: " + PebbleDbForceSync)
-	}
 }
 
 type PebbleDB struct {
 	db *pebble.DB
+
+	// defaultSync is read with atomic.Bool so SetDefaultSyncMode can be
+	// called safely while Set/Delete run concurrently on other goroutines.
+	defaultSync atomic.Bool
 }
 
 var _ DB = (*PebbleDB)(nil)
@@ -70,11 +55,35 @@ func NewPebbleDBWithOpts(name string, dir string, opts *pebble.Options) (*Pebble
 		return nil, err
 	}
 
+	// pebble.Open takes its own reference on opts.Cache; drop ours so the
+	// cache is freed when the DB (the last remaining owner) closes.
+	if opts.Cache != nil {
+		opts.Cache.Unref()
+	}
+
 	return &PebbleDB{
 		db: p,
 	}, nil
 }
 
+// SetDefaultSyncMode sets whether Set, Delete, DeleteRange and Batch.Write
+// sync to disk before returning. It defaults to false (NoSync), matching the behavior before
+// this method existed. Callers that want every write durable without
+// threading SetSync/DeleteSync through every call site (e.g. to recover
+// from a previous unclean shutdown) can opt in with
+// SetDefaultSyncMode(true); SetSync and DeleteSync are unaffected, since
+// they already always sync.
+func (db *PebbleDB) SetDefaultSyncMode(sync bool) {
+	db.defaultSync.Store(sync)
+}
+
+func (db *PebbleDB) writeOpts() *pebble.WriteOptions {
+	if db.defaultSync.Load() {
+		return pebble.Sync
+	}
+	return pebble.NoSync
+}
+
 // Get implements DB.
 func (db *PebbleDB) Get(key []byte) ([]byte, error) {
 	if len(key) == 0 {
@@ -117,12 +126,7 @@ func (db *PebbleDB) Set(key []byte, value []byte) error {
 		return errValueNil
 	}
 
-	wopts := pebble.NoSync
-	if isPebbleDbForceSync {
-		wopts = pebble.Sync
-	}
-
-	err := db.db.Set(key, value, wopts)
+	err := db.db.Set(key, value, db.writeOpts())
 	if err != nil {
 		return err
 	}
@@ -154,12 +158,7 @@ func (db *PebbleDB) Delete(key []byte) error {
 		return errKeyEmpty
 	}
 
-	wopts := pebble.NoSync
-	if isPebbleDbForceSync {
-		wopts = pebble.Sync
-	}
-
-	err := db.db.Delete(key, wopts)
+	err := db.db.Delete(key, db.writeOpts())
 	if err != nil {
 		return err
 	}
@@ -210,7 +209,72 @@ func (db *PebbleDB) Print() error {
 
 // Stats implements DB.
 func (db *PebbleDB) Stats() map[string]string {
-	return nil
+	m := db.Metrics()
+
+	stats := make(map[string]string)
+
+	stats["pebble.compact.count"] = fmt.Sprintf("%d", m.Compact.Count)
+	stats["pebble.flush.count"] = fmt.Sprintf("%d", m.Flush.Count)
+
+	stats["pebble.memtable.count"] = fmt.Sprintf("%d", m.MemTable.Count)
+	stats["pebble.memtable.size"] = fmt.Sprintf("%d", m.MemTable.Size)
+
+	stats["pebble.wal.files"] = fmt.Sprintf("%d", m.WAL.Files)
+	stats["pebble.wal.size"] = fmt.Sprintf("%d", m.WAL.Size)
+	stats["pebble.wal.bytes-written"] = fmt.Sprintf("%d", m.WAL.BytesWritten)
+
+	hits, misses := m.BlockCache.Hits, m.BlockCache.Misses
+	hitRatio := 0.0
+	if total := hits + misses; total > 0 {
+		hitRatio = float64(hits) / float64(total)
+	}
+	stats["pebble.block-cache.size"] = fmt.Sprintf("%d", m.BlockCache.Size)
+	stats["pebble.block-cache.hit-ratio"] = fmt.Sprintf("%.4f", hitRatio)
+
+	for i, level := range m.Levels {
+		stats[fmt.Sprintf("pebble.level.%d.num-files", i)] = fmt.Sprintf("%d", level.NumFiles)
+		stats[fmt.Sprintf("pebble.level.%d.size", i)] = fmt.Sprintf("%d", level.Size)
+	}
+
+	stats["pebble.disk-usage"] = fmt.Sprintf("%d", m.DiskSpaceUsage())
+
+	return stats
+}
+
+// Metrics returns the raw metrics reported by the underlying Pebble instance.
+func (db *PebbleDB) Metrics() *pebble.Metrics {
+	return db.db.Metrics()
+}
+
+// MetricsRegistry is implemented by a caller's metrics system (e.g. prometheus).
+type MetricsRegistry interface {
+	// SetGauge reports the current value of the named gauge.
+	SetGauge(name string, value float64)
+}
+
+// RegisterMetrics reports a curated subset of the DB's metrics to registry as gauges.
+func (db *PebbleDB) RegisterMetrics(prefix string, registry MetricsRegistry) {
+	m := db.Metrics()
+
+	registry.SetGauge(prefix+"compact_count", float64(m.Compact.Count))
+	registry.SetGauge(prefix+"flush_count", float64(m.Flush.Count))
+	registry.SetGauge(prefix+"memtable_count", float64(m.MemTable.Count))
+	registry.SetGauge(prefix+"memtable_size_bytes", float64(m.MemTable.Size))
+	registry.SetGauge(prefix+"wal_size_bytes", float64(m.WAL.Size))
+	registry.SetGauge(prefix+"wal_bytes_written", float64(m.WAL.BytesWritten))
+	registry.SetGauge(prefix+"block_cache_size_bytes", float64(m.BlockCache.Size))
+
+	hits, misses := m.BlockCache.Hits, m.BlockCache.Misses
+	hitRatio := 0.0
+	if total := hits + misses; total > 0 {
+		hitRatio = float64(hits) / float64(total)
+	}
+	registry.SetGauge(prefix+"block_cache_hit_ratio", hitRatio)
+
+	for i, level := range m.Levels {
+		registry.SetGauge(fmt.Sprintf("%slevel_%d_num_files", prefix, i), float64(level.NumFiles))
+		registry.SetGauge(fmt.Sprintf("%slevel_%d_size_bytes", prefix, i), float64(level.Size))
+	}
 }
 
 // NewBatch implements DB.
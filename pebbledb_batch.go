@@ -0,0 +1,85 @@
+//go:build pebbledb
+// +build pebbledb
+
+package db
+
+import (
+	"github.com/cockroachdb/pebble"
+)
+
+type pebbleDBBatch struct {
+	db    *PebbleDB
+	batch *pebble.Batch
+}
+
+var _ Batch = (*pebbleDBBatch)(nil)
+
+func newPebbleDBBatch(db *PebbleDB) *pebbleDBBatch {
+	return &pebbleDBBatch{
+		db:    db,
+		batch: db.db.NewBatch(),
+	}
+}
+
+// Set implements Batch.
+func (b *pebbleDBBatch) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete implements Batch.
+func (b *pebbleDBBatch) Delete(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	return b.batch.Delete(key, nil)
+}
+
+// Write implements Batch.
+func (b *pebbleDBBatch) Write() error {
+	return b.write(b.db.writeOpts())
+}
+
+// WriteSync implements Batch.
+func (b *pebbleDBBatch) WriteSync() error {
+	return b.write(pebble.Sync)
+}
+
+func (b *pebbleDBBatch) write(wopts *pebble.WriteOptions) error {
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	err := b.batch.Commit(wopts)
+	if err != nil {
+		return err
+	}
+
+	return b.Close()
+}
+
+// Close implements Batch. It is safe to call multiple times, including
+// after Write/WriteSync.
+func (b *pebbleDBBatch) Close() error {
+	if b.batch == nil {
+		return nil
+	}
+
+	err := b.batch.Close()
+	b.batch = nil
+
+	return err
+}
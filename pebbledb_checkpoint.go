@@ -0,0 +1,25 @@
+//go:build pebbledb
+// +build pebbledb
+
+package db
+
+import (
+	"github.com/cockroachdb/pebble"
+)
+
+var _ Checkpointer = (*PebbleDB)(nil)
+
+// Checkpoint implements Checkpointer.
+func (db *PebbleDB) Checkpoint(destDir string, opts ...CheckpointOption) error {
+	cfg := &checkpointConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var pebbleOpts []pebble.CheckpointOption
+	if cfg.flushWAL {
+		pebbleOpts = append(pebbleOpts, pebble.WithFlushedWAL())
+	}
+
+	return db.db.Checkpoint(destDir, pebbleOpts...)
+}
@@ -0,0 +1,28 @@
+//go:build pebbledb
+// +build pebbledb
+
+package db
+
+// DeleteRange implements DB, using Pebble's native batch.DeleteRange,
+// which writes a single range tombstone regardless of how many keys fall
+// within [start, end).
+func (db *PebbleDB) DeleteRange(start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+
+	return db.db.DeleteRange(start, end, db.writeOpts())
+}
+
+// DeleteRange implements Batch, staging a single range tombstone in the
+// batch instead of one delete per key.
+func (b *pebbleDBBatch) DeleteRange(start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+
+	return b.batch.DeleteRange(start, end, nil)
+}
@@ -0,0 +1,85 @@
+//go:build pebbledb
+// +build pebbledb
+
+package db
+
+import (
+	"github.com/cockroachdb/pebble"
+)
+
+type pebbleDBIterator struct {
+	source     *pebble.Iterator
+	start, end []byte
+	isReverse  bool
+	isInvalid  bool
+}
+
+var _ Iterator = (*pebbleDBIterator)(nil)
+
+func newPebbleDBIterator(source *pebble.Iterator, start, end []byte, isReverse bool) *pebbleDBIterator {
+	return &pebbleDBIterator{
+		source:    source,
+		start:     start,
+		end:       end,
+		isReverse: isReverse,
+		isInvalid: !source.Valid(),
+	}
+}
+
+// Domain implements Iterator.
+func (itr *pebbleDBIterator) Domain() ([]byte, []byte) {
+	return itr.start, itr.end
+}
+
+// Valid implements Iterator.
+func (itr *pebbleDBIterator) Valid() bool {
+	if itr.isInvalid {
+		return false
+	}
+
+	if !itr.source.Valid() {
+		itr.isInvalid = true
+		return false
+	}
+
+	return true
+}
+
+// Next implements Iterator.
+func (itr *pebbleDBIterator) Next() {
+	itr.assertIsValid()
+
+	if itr.isReverse {
+		itr.source.Prev()
+	} else {
+		itr.source.Next()
+	}
+}
+
+// Key implements Iterator.
+func (itr *pebbleDBIterator) Key() []byte {
+	itr.assertIsValid()
+	return cp(itr.source.Key())
+}
+
+// Value implements Iterator.
+func (itr *pebbleDBIterator) Value() []byte {
+	itr.assertIsValid()
+	return cp(itr.source.Value())
+}
+
+// Error implements Iterator.
+func (itr *pebbleDBIterator) Error() error {
+	return itr.source.Error()
+}
+
+// Close implements Iterator.
+func (itr *pebbleDBIterator) Close() error {
+	return itr.source.Close()
+}
+
+func (itr *pebbleDBIterator) assertIsValid() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+}
@@ -0,0 +1,162 @@
+//go:build pebbledb
+// +build pebbledb
+
+package db
+
+import (
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+)
+
+// LevelOptions tunes a single LSM level of a PebbleDB.
+type LevelOptions struct {
+	// BlockSize is the target SSTable block size for this level. Zero keeps pebble's default.
+	BlockSize int
+
+	// TargetFileSize is the target SSTable size for this level. Zero keeps pebble's default.
+	TargetFileSize int64
+}
+
+// PebbleOptions tunes a PebbleDB instance. The zero value matches
+// NewPebbleDB's previous behavior (pebble's own defaults).
+type PebbleOptions struct {
+	// CacheSizeBytes sizes the shared block cache. Zero keeps pebble's default.
+	CacheSizeBytes int64
+
+	// BloomFilterBits is the bloom filter's bits per key. Zero disables it.
+	BloomFilterBits int
+
+	// MemTableSize bounds an individual memtable's size. Zero keeps pebble's default.
+	MemTableSize uint64
+
+	// MaxOpenFiles bounds pebble's open file count. Zero keeps pebble's default.
+	MaxOpenFiles int
+
+	// DisableWAL disables the write-ahead log. Unsafe outside ephemeral/test DBs.
+	DisableWAL bool
+
+	// Ephemeral marks this DB as disposable: disables the WAL and defaults to NoSync.
+	Ephemeral bool
+
+	// DefaultSync sets the default sync mode at construction (see SetDefaultSyncMode).
+	DefaultSync bool
+
+	// Levels overrides pebble's per-level options, starting from L0.
+	Levels []LevelOptions
+}
+
+// defaultPebbleLevelCount matches pebble's own default level count.
+const defaultPebbleLevelCount = 7
+
+// toPebbleOptions translates cfg into a *pebble.Options ready to be passed
+// to NewPebbleDBWithOpts.
+func (cfg PebbleOptions) toPebbleOptions() *pebble.Options {
+	opts := &pebble.Options{
+		MaxOpenFiles: cfg.MaxOpenFiles,
+		MemTableSize: cfg.MemTableSize,
+		DisableWAL:   cfg.DisableWAL || cfg.Ephemeral,
+	}
+
+	if cfg.CacheSizeBytes > 0 {
+		opts.Cache = pebble.NewCache(cfg.CacheSizeBytes)
+	}
+
+	var filter pebble.FilterPolicy
+	if cfg.BloomFilterBits > 0 {
+		filter = bloom.FilterPolicy(cfg.BloomFilterBits)
+	}
+
+	// Build opts.Levels whenever a filter is set, independent of Levels,
+	// or a filter-only config would silently get no filter attached.
+	if filter != nil || len(cfg.Levels) > 0 {
+		levelCount := len(cfg.Levels)
+		if levelCount < defaultPebbleLevelCount {
+			levelCount = defaultPebbleLevelCount
+		}
+
+		opts.Levels = make([]pebble.LevelOptions, levelCount)
+		for i := range opts.Levels {
+			opts.Levels[i].FilterPolicy = filter
+			if i < len(cfg.Levels) {
+				opts.Levels[i].BlockSize = cfg.Levels[i].BlockSize
+				opts.Levels[i].TargetFileSize = cfg.Levels[i].TargetFileSize
+			}
+		}
+	}
+
+	opts.EnsureDefaults()
+
+	return opts
+}
+
+// NewPebbleDBWithConfig opens (or creates) a PebbleDB at dir/name.db using the tuning knobs in cfg.
+func NewPebbleDBWithConfig(name string, dir string, cfg PebbleOptions) (*PebbleDB, error) {
+	db, err := NewPebbleDBWithOpts(name, dir, cfg.toPebbleOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DefaultSync && !cfg.Ephemeral {
+		db.SetDefaultSyncMode(true)
+	}
+
+	return db, nil
+}
+
+var (
+	registeredPebbleOptionsMu sync.Mutex
+	registeredPebbleOptions   PebbleOptions
+)
+
+// RegisterPebbleOptions sets the PebbleOptions used by NewDB(name, PebbleDBBackend, dir).
+func RegisterPebbleOptions(cfg PebbleOptions) {
+	registeredPebbleOptionsMu.Lock()
+	defer registeredPebbleOptionsMu.Unlock()
+	registeredPebbleOptions = cfg
+}
+
+func currentPebbleOptions() PebbleOptions {
+	registeredPebbleOptionsMu.Lock()
+	defer registeredPebbleOptionsMu.Unlock()
+	return registeredPebbleOptions
+}
+
+// These env vars override the registered PebbleOptions field of the same name.
+const (
+	envPebbleCacheSizeBytes  = "PEBBLEDB_CACHE_SIZE_BYTES"
+	envPebbleBloomFilterBits = "PEBBLEDB_BLOOM_FILTER_BITS"
+	envPebbleMaxOpenFiles    = "PEBBLEDB_MAX_OPEN_FILES"
+	envPebbleEphemeral       = "PEBBLEDB_EPHEMERAL"
+)
+
+func pebbleOptionsFromEnv(cfg PebbleOptions) PebbleOptions {
+	if v, ok := os.LookupEnv(envPebbleCacheSizeBytes); ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.CacheSizeBytes = n
+		}
+	}
+
+	if v, ok := os.LookupEnv(envPebbleBloomFilterBits); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.BloomFilterBits = n
+		}
+	}
+
+	if v, ok := os.LookupEnv(envPebbleMaxOpenFiles); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MaxOpenFiles = n
+		}
+	}
+
+	if v, ok := os.LookupEnv(envPebbleEphemeral); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Ephemeral = b
+		}
+	}
+
+	return cfg
+}
@@ -0,0 +1,91 @@
+//go:build pebbledb
+// +build pebbledb
+
+package db
+
+import (
+	"github.com/cockroachdb/pebble"
+)
+
+var _ Snapshotter = (*PebbleDB)(nil)
+
+// Snapshot implements Snapshotter.
+func (db *PebbleDB) Snapshot() (Snapshot, error) {
+	return &pebbleDBSnapshot{snap: db.db.NewSnapshot()}, nil
+}
+
+type pebbleDBSnapshot struct {
+	snap *pebble.Snapshot
+}
+
+var _ Snapshot = (*pebbleDBSnapshot)(nil)
+
+// Get implements Snapshot.
+func (s *pebbleDBSnapshot) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errKeyEmpty
+	}
+
+	res, closer, err := s.snap.Get(key)
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer closer.Close()
+
+	return cp(res), nil
+}
+
+// Has implements Snapshot.
+func (s *pebbleDBSnapshot) Has(key []byte) (bool, error) {
+	bytes, err := s.Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes != nil, nil
+}
+
+func (s *pebbleDBSnapshot) newIter(start, end []byte) (*pebble.Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, errKeyEmpty
+	}
+
+	o := pebble.IterOptions{
+		LowerBound: start,
+		UpperBound: end,
+	}
+
+	return s.snap.NewIter(&o)
+}
+
+// Iterator implements Snapshot.
+func (s *pebbleDBSnapshot) Iterator(start, end []byte) (Iterator, error) {
+	itr, err := s.newIter(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	itr.First()
+
+	return newPebbleDBIterator(itr, start, end, false), nil
+}
+
+// ReverseIterator implements Snapshot.
+func (s *pebbleDBSnapshot) ReverseIterator(start, end []byte) (Iterator, error) {
+	itr, err := s.newIter(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	itr.Last()
+
+	return newPebbleDBIterator(itr, start, end, true), nil
+}
+
+// Release implements Snapshot.
+func (s *pebbleDBSnapshot) Release() {
+	_ = s.snap.Close()
+}
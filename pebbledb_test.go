@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/cockroachdb/pebble"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,4 +33,326 @@ func TestWithPebbleDB(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("PebbleDB", func(t *testing.T) { Run(t, db) })
+	t.Run("BatchDeleteRangeSemantics", func(t *testing.T) {
+		RunBatchDeleteRangeSemantics(t, db)
+	})
+}
+
+func TestNewPebbleDBWithConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg := PebbleOptions{
+		CacheSizeBytes:  4 << 20,
+		BloomFilterBits: 10,
+		MaxOpenFiles:    500,
+		Ephemeral:       true,
+		Levels: []LevelOptions{
+			{BlockSize: 16 << 10, TargetFileSize: 2 << 20},
+		},
+	}
+
+	db, err := NewPebbleDBWithConfig("config_test", dir, cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Set([]byte("k"), []byte("v")))
+
+	val, err := db.Get([]byte("k"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v"), val)
+}
+
+func TestPebbleDBSetDefaultSyncMode(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("default_sync_mode_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Defaults to NoSync, same as before SetDefaultSyncMode existed.
+	require.NoError(t, db.Set([]byte("k"), []byte("v1")))
+
+	db.SetDefaultSyncMode(true)
+	require.NoError(t, db.Set([]byte("k"), []byte("v2")))
+
+	val, err := db.Get([]byte("k"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), val)
+
+	db.SetDefaultSyncMode(false)
+	require.NoError(t, db.Delete([]byte("k")))
+
+	val, err = db.Get([]byte("k"))
+	require.NoError(t, err)
+	assert.Nil(t, val)
+}
+
+func TestPebbleDBDeleteRange(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("delete_range_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, db.Set([]byte(k), []byte(k)))
+	}
+
+	require.NoError(t, db.DeleteRange([]byte("b"), []byte("d")))
+
+	for _, tc := range []struct {
+		key     string
+		deleted bool
+	}{
+		{"a", false},
+		{"b", true},
+		{"c", true},
+		{"d", false},
+		{"e", false},
+	} {
+		val, err := db.Get([]byte(tc.key))
+		require.NoError(t, err)
+		if tc.deleted {
+			assert.Nil(t, val)
+		} else {
+			assert.Equal(t, []byte(tc.key), val)
+		}
+	}
+}
+
+func TestPebbleDBBatchDeleteRange(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("batch_delete_range_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, db.Set([]byte(k), []byte(k)))
+	}
+
+	batch := db.NewBatch()
+	require.NoError(t, batch.DeleteRange([]byte("b"), []byte("d")))
+	require.NoError(t, batch.Write())
+
+	val, err := db.Get([]byte("b"))
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	val, err = db.Get([]byte("d"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("d"), val)
+}
+
+func TestDeleteRangeFallback(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("delete_range_fallback_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, db.Set([]byte(k), []byte(k)))
+	}
+
+	require.NoError(t, DeleteRangeFallback(db, []byte("b"), []byte("d")))
+
+	val, err := db.Get([]byte("b"))
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	val, err = db.Get([]byte("d"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("d"), val)
+}
+
+// benchDeleteRangeKeys matches the 1M-key prefix scenario from the
+// pebbledb pruning work in cosmos-sdk store/v2: deleting an entire key
+// prefix should cost roughly one write regardless of how many keys it
+// spans.
+const benchDeleteRangeKeys = 1_000_000
+
+func setupDeleteRangeBench(b *testing.B) *PebbleDB {
+	b.Helper()
+
+	dir := b.TempDir()
+	db, err := NewPebbleDB("delete_range_bench", dir)
+	require.NoError(b, err)
+
+	populateDeleteRangeBenchKeys(b, db)
+
+	return db
+}
+
+// populateDeleteRangeBenchKeys (re)writes the 1M-key prefix so each
+// benchmark iteration deletes a full prefix rather than an already-empty
+// range.
+func populateDeleteRangeBenchKeys(b *testing.B, db *PebbleDB) {
+	b.Helper()
+
+	batch := db.NewBatch()
+	for i := 0; i < benchDeleteRangeKeys; i++ {
+		k := []byte(fmt.Sprintf("prefix/%08d", i))
+		require.NoError(b, batch.Set(k, k))
+	}
+	require.NoError(b, batch.Write())
+	require.NoError(b, batch.Close())
+}
+
+func BenchmarkPebbleDBDeleteRange(b *testing.B) {
+	db := setupDeleteRangeBench(b)
+	defer db.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		populateDeleteRangeBenchKeys(b, db)
+		b.StartTimer()
+
+		require.NoError(b, db.DeleteRange([]byte("prefix/"), []byte("prefix0")))
+	}
+}
+
+func BenchmarkPebbleDBDeleteRangeFallback(b *testing.B) {
+	db := setupDeleteRangeBench(b)
+	defer db.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		populateDeleteRangeBenchKeys(b, db)
+		b.StartTimer()
+
+		require.NoError(b, DeleteRangeFallback(db, []byte("prefix/"), []byte("prefix0")))
+	}
+}
+
+func TestPebbleDBCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("checkpoint_src", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for i := 0; i < 100; i++ {
+		k := []byte(fmt.Sprintf("key-%03d", i))
+		v := []byte(fmt.Sprintf("val-%03d", i))
+		require.NoError(t, db.Set(k, v))
+	}
+
+	checkpointDir := filepath.Join(dir, "checkpoint.db")
+	require.NoError(t, db.Checkpoint(checkpointDir, WithFlushedWAL()))
+
+	// The source DB keeps accepting writes after the checkpoint was taken;
+	// these must not show up in the checkpoint.
+	require.NoError(t, db.Set([]byte("key-100"), []byte("val-100")))
+
+	checkpoint, err := NewPebbleDBWithOpts("checkpoint", dir, &pebble.Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer checkpoint.Close()
+
+	for i := 0; i < 100; i++ {
+		k := []byte(fmt.Sprintf("key-%03d", i))
+		want := []byte(fmt.Sprintf("val-%03d", i))
+
+		got, err := checkpoint.Get(k)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	ok, err := checkpoint.Has([]byte("key-100"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPebbleOptionsBloomFilterWithoutLevels(t *testing.T) {
+	cfg := PebbleOptions{BloomFilterBits: 10}
+
+	opts := cfg.toPebbleOptions()
+	require.NotEmpty(t, opts.Levels)
+
+	for i, l := range opts.Levels {
+		assert.NotNilf(t, l.FilterPolicy, "level %d has no filter policy", i)
+	}
+}
+
+func TestPebbleDBSnapshotRepeatableRead(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("snapshot_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Set([]byte("k"), []byte("v1")))
+
+	snap, err := db.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+
+	// Writes against the parent DB after the snapshot was taken must not be
+	// visible through it.
+	require.NoError(t, db.Set([]byte("k"), []byte("v2")))
+	require.NoError(t, db.Set([]byte("k2"), []byte("v3")))
+
+	val, err := snap.Get([]byte("k"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), val)
+
+	ok, err := snap.Has([]byte("k2"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	current, err := db.Get([]byte("k"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), current)
+}
+
+func TestPebbleDBSnapshotIterator(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("snapshot_iter_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Set([]byte("a"), []byte("1")))
+	require.NoError(t, db.Set([]byte("b"), []byte("2")))
+
+	snap, err := db.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+
+	require.NoError(t, db.Set([]byte("c"), []byte("3")))
+
+	itr, err := snap.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer itr.Close()
+
+	var keys []string
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, string(itr.Key()))
+	}
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
+
+// TestPebbleDBSnapshotLeak exercises many concurrently-open, unreleased
+// snapshots: Pebble pins every unreleased snapshot's state, so the DB must
+// stay usable and each snapshot must keep observing its own point-in-time
+// view until it is released.
+func TestPebbleDBSnapshotLeak(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("snapshot_leak_test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Set([]byte("k"), []byte("v")))
+
+	snaps := make([]Snapshot, 0, 100)
+	for i := 0; i < 100; i++ {
+		snap, err := db.Snapshot()
+		require.NoError(t, err)
+		snaps = append(snaps, snap)
+	}
+
+	for _, snap := range snaps {
+		val, err := snap.Get([]byte("k"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v"), val)
+	}
+
+	for _, snap := range snaps {
+		snap.Release()
+	}
 }
@@ -0,0 +1,48 @@
+package db
+
+import "errors"
+
+var (
+	errKeyEmpty    = errors.New("key cannot be empty")
+	errValueNil    = errors.New("value cannot be nil")
+	errBatchClosed = errors.New("batch has been written or closed")
+)
+
+// cp returns a copy of bz, so callers never retain a reference into a
+// backend's internal buffers.
+func cp(bz []byte) (ret []byte) {
+	ret = make([]byte, len(bz))
+	copy(ret, bz)
+	return ret
+}
+
+// DeleteRangeFallback deletes every key in [start, end) from db by
+// iterating the range and deleting each key within a single batch. It is
+// meant for DB backends without a native range-delete primitive (e.g.
+// goleveldb, memdb), so they can implement DB.DeleteRange without each
+// reimplementing the same iterate-and-delete loop.
+func DeleteRangeFallback(db DB, start, end []byte) error {
+	if len(start) == 0 || len(end) == 0 {
+		return errKeyEmpty
+	}
+
+	itr, err := db.Iterator(start, end)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		if err := batch.Delete(itr.Key()); err != nil {
+			return err
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+
+	return batch.Write()
+}